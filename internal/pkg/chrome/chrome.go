@@ -2,9 +2,16 @@ package chrome
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -12,49 +19,468 @@ import (
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xerror"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xexec"
 	"github.com/thecodingmachine/gotenberg/internal/pkg/xlog"
-	"github.com/thecodingmachine/gotenberg/internal/pkg/xtime"
 )
 
-// Start starts Google Chrome headless in background.
-func Start(logger xlog.Logger, ignoreCertificateErrors bool) error {
+// maxConcurrentInstances bounds how many ephemeral, per-request Chrome
+// headless processes may run at once (see StartEphemeral), so that a burst
+// of conversions cannot exhaust the host's memory.
+const maxConcurrentInstances int = 4
+
+// endpoint is the DevTools HTTP endpoint used to reach the Google Chrome
+// headless instance started by Start, gotenberg's single long-lived
+// process. It defaults to the local instance bound to port 9222, but is
+// overridden by Connect when gotenberg is configured to use a remote/
+// shared Chrome instead.
+var endpoint = "http://localhost:9222"
+
+// remote is true when gotenberg is connected to a Chrome instance it does
+// not own the lifecycle of (see Connect). Start and its supervisor are
+// simply never invoked in that case, as there is no local process to
+// spawn, kill or restart.
+var remote = false
+
+// ephemeralSemaphore caps the number of ephemeral Chrome processes running
+// concurrently at maxConcurrentInstances.
+var ephemeralSemaphore = make(chan struct{}, maxConcurrentInstances)
+
+// launchOptions holds the Chrome headless launch parameters that vary
+// between a process instance: the DevTools port it listens on and, for
+// ephemeral instances, the user-data-dir holding its own profile.
+type launchOptions struct {
+	port           int
+	userDataDir    string
+	disableSandbox bool
+	launcher       LauncherOptions
+}
+
+// LauncherOptions let callers customize a Chrome headless launch on top of
+// gotenberg's own defaults, so that deployments behind a corporate proxy,
+// or requests targeting URLs Chrome would otherwise refuse, can still be
+// served. They may be set once at boot (the defaults gotenberg launches
+// Chrome with) and overridden on a per-request basis, e.g. via the
+// "Gotenberg-Chrome-Proxy" HTTP header or a dedicated multipart field.
+type LauncherOptions struct {
+	// ProxyServer is passed as Chrome's --proxy-server, e.g.
+	// "http://10.0.0.1:3128" or "socks5://10.0.0.1:1080".
+	ProxyServer string
+	// Locale is passed as Chrome's --lang, e.g. "fr-FR".
+	Locale string
+	// AllowedPorts is passed as Chrome's --explicitly-allowed-ports, so
+	// that URLs targeting a port Chrome considers unsafe are not rejected.
+	AllowedPorts []int
+	// ExtraArgs are additional Chrome CLI flags. Only flags matching
+	// allowedExtraArgPrefixes are honored; anything else is dropped and
+	// logged, as Chrome's process model is not something callers should
+	// otherwise be able to tamper with.
+	ExtraArgs []string
+}
+
+// allowedExtraArgPrefixes is the allow-list of additional Chrome CLI flags
+// LauncherOptions.ExtraArgs may contain. It intentionally excludes
+// anything that would let a caller interfere with gotenberg's own process
+// model (e.g. --remote-debugging-port, --user-data-dir): those remain
+// internal to cmd.
+var allowedExtraArgPrefixes = []string{
+	"--disable-features=",
+	"--enable-features=",
+	"--user-agent=",
+}
+
+// sanitizeExtraArgs drops any arg that does not match one of
+// allowedExtraArgPrefixes, logging each one dropped at debug level.
+func sanitizeExtraArgs(logger xlog.Logger, extraArgs []string) []string {
+	const op string = "chrome.sanitizeExtraArgs"
+	sanitized := make([]string, 0, len(extraArgs))
+	for _, arg := range extraArgs {
+		allowed := false
+		for _, prefix := range allowedExtraArgPrefixes {
+			if strings.HasPrefix(arg, prefix) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			logger.DebugOpf(op, "rejecting disallowed Chrome launch flag '%s'", arg)
+			continue
+		}
+		sanitized = append(sanitized, arg)
+	}
+	return sanitized
+}
+
+// mustDisableSandbox reports whether Chrome's own sandbox has to be
+// disabled via --no-sandbox for it to run at all. This is only the case on
+// Linux when gotenberg runs as root, its most common deployment (a root
+// container without a user namespace remapping): the Linux sandbox relies
+// on a SUID helper gotenberg does not ship, and Chrome refuses to start as
+// root without --no-sandbox. Everywhere else — macOS dev machines, or
+// Linux containers already running as a non-root user, typically through
+// user-namespace remapping — Chrome can and should keep its sandbox on.
+//
+// CHROME_DISABLE_SANDBOX, when set, overrides this detection so that users
+// who explicitly want the old always-disabled behavior (or the opposite)
+// can force it.
+func mustDisableSandbox() bool {
+	if override, ok := os.LookupEnv("CHROME_DISABLE_SANDBOX"); ok {
+		return override == "1" || strings.EqualFold(override, "true")
+	}
+	return runtime.GOOS == "linux" && os.Geteuid() == 0
+}
+
+// Instance represents a single, ephemeral Chrome headless process started
+// by StartEphemeral, along with the resources tied to its lifetime (its
+// user-data-dir, its own DevTools endpoint). Unlike the process managed by
+// Start, an Instance belongs to a single print request: it is never
+// shared, so cookies, cache or localStorage from one conversion can never
+// bleed into another, and a stuck tab only ever affects the request that
+// caused it.
+type Instance struct {
+	proc        *os.Process
+	endpoint    string
+	userDataDir string
+	closeOnce   sync.Once
+}
+
+// maxEphemeralLaunchAttempts bounds how many times StartEphemeral retries a
+// failed launch on a freshly allocated port and user-data-dir, before
+// giving up. freePort hands out a port that is free at the time it is
+// probed, but nothing reserves it in between: a concurrent StartEphemeral
+// call (or any other process on the host) can bind it first, so Chrome can
+// fail to come up on it. Retrying on a new port is cheap and makes that
+// race harmless rather than fatal.
+const maxEphemeralLaunchAttempts int = 3
+
+// StartEphemeral starts a short-lived Google Chrome headless process
+// dedicated to a single print request, with its own dynamically allocated
+// debugging port and its own --user-data-dir. The number of Chrome
+// processes running at once is bounded by maxConcurrentInstances; callers
+// may block waiting for a free slot. Callers must call Close on the
+// returned Instance once the request has been handled.
+func StartEphemeral(logger xlog.Logger, ignoreCertificateErrors bool, launcher LauncherOptions) (*Instance, error) {
+	const op string = "chrome.StartEphemeral"
+	logger.DebugOp(op, "starting new ephemeral Google Chrome headless process...")
+	ephemeralSemaphore <- struct{}{}
+	resolver := func() (*Instance, error) {
+		var lastErr error
+		for attempt := 1; attempt <= maxEphemeralLaunchAttempts; attempt++ {
+			instance, err := launchEphemeral(logger, ignoreCertificateErrors, launcher)
+			if err == nil {
+				return instance, nil
+			}
+			logger.DebugOpf(op, "ephemeral launch attempt %d/%d failed: %v", attempt, maxEphemeralLaunchAttempts, err)
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+	instance, err := resolver()
+	if err != nil {
+		<-ephemeralSemaphore
+		return nil, xerror.New(op, err)
+	}
+	return instance, nil
+}
+
+// launchEphemeral makes a single attempt at starting an ephemeral Chrome
+// headless process on a freshly allocated port and user-data-dir; see
+// StartEphemeral.
+func launchEphemeral(logger xlog.Logger, ignoreCertificateErrors bool, launcher LauncherOptions) (*Instance, error) {
+	userDataDir, err := ioutil.TempDir("", "gotenberg-chrome")
+	if err != nil {
+		return nil, err
+	}
+	port, err := freePort()
+	if err != nil {
+		os.RemoveAll(userDataDir)
+		return nil, err
+	}
+	opts := launchOptions{port: port, userDataDir: userDataDir, disableSandbox: mustDisableSandbox(), launcher: launcher}
+	c, err := cmd(logger, ignoreCertificateErrors, opts)
+	if err != nil {
+		os.RemoveAll(userDataDir)
+		return nil, err
+	}
+	xexec.LogBeforeExecute(logger, c)
+	if err := c.Start(); err != nil {
+		os.RemoveAll(userDataDir)
+		return nil, err
+	}
+	instance := &Instance{
+		proc:        c.Process,
+		endpoint:    fmt.Sprintf("http://localhost:%d", port),
+		userDataDir: userDataDir,
+	}
+	if isViable, viableErr := isViableAt(logger, instance.endpoint, ephemeralViabilityBackoffMax, maxEphemeralViabilityTests); !isViable {
+		killProcess(logger, instance.proc)
+		os.RemoveAll(userDataDir)
+		return nil, viableErr
+	}
+	return instance, nil
+}
+
+// IsViable checks if this ephemeral Chrome instance is healthy.
+func (instance *Instance) IsViable(logger xlog.Logger) (bool, error) {
+	return isViableAt(logger, instance.endpoint, ephemeralViabilityBackoffMax, maxEphemeralViabilityTests)
+}
+
+// Close kills the ephemeral Chrome process, removes its user-data-dir and
+// releases its slot in the concurrency pool. It is a no-op on every call
+// after the first, so a caller closing the same Instance twice (e.g. once
+// on an error path and once in a deferred cleanup) never over-releases the
+// concurrency pool's semaphore.
+func (instance *Instance) Close(logger xlog.Logger) error {
+	const op string = "chrome.Instance.Close"
+	var err error
+	instance.closeOnce.Do(func() {
+		defer func() { <-ephemeralSemaphore }()
+		logger.DebugOpf(op, "closing ephemeral Google Chrome headless process using endpoint '%s'...", instance.endpoint)
+		err = killProcess(logger, instance.proc)
+		if rmErr := os.RemoveAll(instance.userDataDir); err == nil {
+			err = rmErr
+		}
+	})
+	if err != nil {
+		return xerror.New(op, err)
+	}
+	return nil
+}
+
+// freePort asks the kernel for a free open port that is ready to use.
+func freePort() (int, error) {
+	addr, err := net.ResolveTCPAddr("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	l, err := net.ListenTCP("tcp", addr)
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// restartBackoffMin and restartBackoffMax bound the exponential backoff the
+// supervisor waits between restart attempts: it starts at
+// restartBackoffMin and doubles up to restartBackoffMax. maxRestartAttempts
+// caps how many consecutive restart attempts the supervisor makes before
+// giving up and leaving Chrome unhealthy for good, instead of retrying
+// forever. These are vars rather than consts so that tests can shrink them
+// instead of waiting out the real backoff.
+var (
+	restartBackoffMin  = 250 * time.Millisecond
+	restartBackoffMax  = 8 * time.Second
+	maxRestartAttempts = 10
+)
+
+// maxViabilityTests bounds how many times IsViable polls the long-lived
+// Chrome process managed by Start before giving up; it shares its backoff
+// cap with the supervisor (restartBackoffMax), since both are tolerating
+// the same slow-boot, coarsely-retried process.
+const maxViabilityTests int = 8
+
+// ephemeralViabilityBackoffMax and maxEphemeralViabilityTests bound the
+// viability poll launchEphemeral and Instance.IsViable run against a
+// per-request Chrome instance. They are deliberately tighter than
+// restartBackoffMax/maxViabilityTests: that budget fits the supervisor's
+// coarse, long-lived crash-loop retries, but a caller waiting on an
+// ephemeral instance holds one of only maxConcurrentInstances slots, so an
+// unresponsive Chrome here should fail fast and retry on a fresh port
+// (see maxEphemeralLaunchAttempts) rather than sit in a poll of about half a
+// minute.
+var (
+	ephemeralViabilityBackoffMax = 2 * time.Second
+	maxEphemeralViabilityTests   = 6
+)
+
+// health is the state of the Chrome headless process supervised by Start,
+// as reported by Health.
+type health int
+
+const (
+	healthStarting health = iota
+	healthHealthy
+	healthUnhealthy
+)
+
+var (
+	healthMu     sync.RWMutex
+	currentState = healthStarting
+	restarts     uint64
+)
+
+func setHealth(h health) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	currentState = h
+}
+
+// Health reports whether the supervised Chrome headless process is
+// currently healthy, along with the number of times the supervisor has
+// restarted it since boot. gotenberg's HTTP server uses this to back its
+// readiness/liveness probes, and to reject incoming print requests with
+// 503 while Chrome is unhealthy, and operators can alarm on the restart
+// count to catch flapping.
+func Health() (healthy bool, restartCount uint64) {
+	healthMu.RLock()
+	defer healthMu.RUnlock()
+	return currentState == healthHealthy, atomic.LoadUint64(&restarts)
+}
+
+// Boot starts Google Chrome according to gotenberg's configuration: when
+// the CHROME_REMOTE_URL environment variable is set, it calls Connect with
+// that value instead of spawning a local process via Start. This is the
+// entry point gotenberg's own startup code should call, so that deployments
+// relying on a shared Chrome fleet only need to set an environment
+// variable rather than change how gotenberg is invoked.
+func Boot(logger xlog.Logger, ignoreCertificateErrors bool, launcher LauncherOptions) error {
+	const op string = "chrome.Boot"
+	if wsURL, ok := os.LookupEnv("CHROME_REMOTE_URL"); ok && wsURL != "" {
+		logger.DebugOpf(op, "CHROME_REMOTE_URL is set, connecting to remote Google Chrome headless process at '%s'...", wsURL)
+		return Connect(logger, wsURL)
+	}
+	return Start(logger, ignoreCertificateErrors, launcher)
+}
+
+// Start starts Google Chrome headless in background and launches a
+// supervisor goroutine that keeps it alive for the lifetime of the
+// process: whenever Chrome exits, the supervisor relaunches it, killing
+// and retrying any launch that fails its viability check, with a capped
+// exponential backoff (from restartBackoffMin up to restartBackoffMax)
+// instead of retrying immediately and forever. A Chrome process that stays
+// up but stops answering is not detected, as the supervisor only watches
+// for the process exiting. After maxRestartAttempts consecutive failures
+// it gives up, leaving Chrome unhealthy (see Health) so that gotenberg's
+// HTTP server can start rejecting print requests instead of hanging.
+func Start(logger xlog.Logger, ignoreCertificateErrors bool, launcher LauncherOptions) error {
 	const op string = "chrome.Start"
+	if remote {
+		logger.DebugOp(op, "connected to a remote Google Chrome headless process, nothing to start")
+		return nil
+	}
 	logger.DebugOp(op, "starting new Google Chrome headless process on port 9222...")
-	resolver := func() error {
-		cmd, err := cmd(logger, ignoreCertificateErrors)
-		if err != nil {
-			return err
+	setHealth(healthStarting)
+	c, err := launchWithRetries(logger, func() (*exec.Cmd, error) {
+		return launchAndVerify(logger, ignoreCertificateErrors, launcher)
+	})
+	if err != nil {
+		setHealth(healthUnhealthy)
+		return xerror.New(op, err)
+	}
+	setHealth(healthHealthy)
+	go supervise(logger, c, func() (*exec.Cmd, error) {
+		return launchAndVerify(logger, ignoreCertificateErrors, launcher)
+	})
+	return nil
+}
+
+// launchAndVerify starts a single Chrome headless process bound to port
+// 9222 and waits for it to become viable, killing it if it never does.
+func launchAndVerify(logger xlog.Logger, ignoreCertificateErrors bool, launcher LauncherOptions) (*exec.Cmd, error) {
+	c, err := cmd(logger, ignoreCertificateErrors, launchOptions{port: 9222, disableSandbox: mustDisableSandbox(), launcher: launcher})
+	if err != nil {
+		return nil, err
+	}
+	xexec.LogBeforeExecute(logger, c)
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	if isViable, viableErr := IsViable(logger); !isViable {
+		killProcess(logger, c.Process)
+		return nil, viableErr
+	}
+	return c, nil
+}
+
+// launchWithRetries calls launch, retrying with a capped exponential
+// backoff (from restartBackoffMin up to restartBackoffMax), and gives up
+// once launch has been called maxRestartAttempts times in all. It does not itself update
+// restarts: whether a successful call counts as a restart depends on the
+// caller (see Start, which is the initial boot and never counts as one,
+// versus supervise, where every successful call does).
+func launchWithRetries(logger xlog.Logger, launch func() (*exec.Cmd, error)) (*exec.Cmd, error) {
+	const op string = "chrome.launchWithRetries"
+	backoff := restartBackoffMin
+	var lastErr error
+	for attempt := 1; attempt <= maxRestartAttempts; attempt++ {
+		if attempt > 1 {
+			logger.DebugOpf(op, "retrying Google Chrome headless process launch, attempt %d/%d after %v", attempt, maxRestartAttempts, backoff)
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > restartBackoffMax {
+				backoff = restartBackoffMax
+			}
 		}
-		// we try to start the process.
-		xexec.LogBeforeExecute(logger, cmd)
-		if err := cmd.Start(); err != nil {
-			return err
+		c, err := launch()
+		if err == nil {
+			return c, nil
 		}
-		// if the process failed to start correctly,
-		// we have to restart it.
-		isViable, _ := IsViable(logger)
-		if !isViable {
-			return restart(logger, cmd.Process, ignoreCertificateErrors)
+		logger.DebugOpf(op, "launch attempt %d/%d failed: %v", attempt, maxRestartAttempts, err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// supervise owns the Chrome headless process lifecycle for the remainder
+// of gotenberg's life. It blocks on cmd.Wait() and, whenever the process
+// exits, restarts it via launchWithRetries; it never recurses, so a Chrome
+// stuck in a crash loop bounds the goroutine's work at maxRestartAttempts
+// instead of blowing the stack. Every successful relaunch increments
+// restarts, regardless of how many attempts launchWithRetries needed
+// internally: from Health's point of view, Chrome restarted exactly once.
+func supervise(logger xlog.Logger, c *exec.Cmd, launch func() (*exec.Cmd, error)) {
+	const op string = "chrome.supervise"
+	for {
+		waitErr := c.Wait()
+		logger.DebugOpf(op, "Google Chrome headless process exited: %v", waitErr)
+		setHealth(healthUnhealthy)
+
+		next, err := launchWithRetries(logger, launch)
+		if err != nil {
+			logger.DebugOpf(op, "giving up on Google Chrome headless process after %d restart attempts: %v", maxRestartAttempts, err)
+			return
 		}
-		return nil
+		atomic.AddUint64(&restarts, 1)
+		setHealth(healthHealthy)
+		c = next
 	}
-	if err := resolver(); err != nil {
+}
+
+// Connect configures gotenberg to use an already running Chrome/Chromium
+// instance reachable at wsURL (its DevTools HTTP endpoint, e.g.
+// "http://host:9222") instead of spawning a local process. It is the
+// entry point for deployments relying on a shared Chrome fleet (a
+// browserless-style pool, a sidecar container, and so on): Start and its
+// supervisor are then skipped entirely, as gotenberg no longer owns the
+// process lifecycle.
+//
+// See Boot, which decides between Connect and Start based on the
+// CHROME_REMOTE_URL environment variable.
+func Connect(logger xlog.Logger, wsURL string) error {
+	const op string = "chrome.Connect"
+	logger.DebugOpf(op, "connecting to remote Google Chrome headless process at '%s'...", wsURL)
+	previousEndpoint := endpoint
+	endpoint = wsURL
+	isViable, err := IsViable(logger)
+	if !isViable {
+		endpoint = previousEndpoint
+		setHealth(healthUnhealthy)
 		return xerror.New(op, err)
 	}
+	remote = true
+	setHealth(healthHealthy)
 	return nil
 }
 
-func cmd(logger xlog.Logger, ignoreCertificateErrors bool) (*exec.Cmd, error) {
+func cmd(logger xlog.Logger, ignoreCertificateErrors bool, opts launchOptions) (*exec.Cmd, error) {
 	const op string = "chrome.cmd"
 	binary := "chromium"
 	args := []string{
-		"--no-sandbox",
 		"--headless",
 		// see https://github.com/thecodingmachine/gotenberg/issues/157.
 		"--disable-dev-shm-usage",
 		// See https://github.com/puppeteer/puppeteer/issues/661
 		// and https://github.com/puppeteer/puppeteer/issues/2410.
 		"--font-render-hinting=none",
-		"--remote-debugging-port=9222",
+		fmt.Sprintf("--remote-debugging-port=%d", opts.port),
 		"--disable-gpu",
 		"--disable-translate",
 		"--disable-extensions",
@@ -68,6 +494,32 @@ func cmd(logger xlog.Logger, ignoreCertificateErrors bool) (*exec.Cmd, error) {
 		"--no-first-run",
 	}
 
+	if opts.userDataDir != "" {
+		args = append(args, fmt.Sprintf("--user-data-dir=%s", opts.userDataDir))
+	}
+
+	if opts.disableSandbox {
+		args = append(args, "--no-sandbox")
+	}
+
+	if opts.launcher.ProxyServer != "" {
+		args = append(args, fmt.Sprintf("--proxy-server=%s", opts.launcher.ProxyServer))
+	}
+
+	if opts.launcher.Locale != "" {
+		args = append(args, fmt.Sprintf("--lang=%s", opts.launcher.Locale))
+	}
+
+	if len(opts.launcher.AllowedPorts) > 0 {
+		allowedPorts := make([]string, len(opts.launcher.AllowedPorts))
+		for i, port := range opts.launcher.AllowedPorts {
+			allowedPorts[i] = strconv.Itoa(port)
+		}
+		args = append(args, fmt.Sprintf("--explicitly-allowed-ports=%s", strings.Join(allowedPorts, ",")))
+	}
+
+	args = append(args, sanitizeExtraArgs(logger, opts.launcher.ExtraArgs)...)
+
 	if ignoreCertificateErrors {
 		args = append(args, "--ignore-certificate-errors")
 	}
@@ -80,72 +532,46 @@ func cmd(logger xlog.Logger, ignoreCertificateErrors bool) (*exec.Cmd, error) {
 	return cmd, nil
 }
 
-func kill(logger xlog.Logger, proc *os.Process) error {
-	const op string = "chrome.kill"
-	logger.DebugOp(op, "killing Google Chrome headless process using port 9222...")
-	resolver := func() error {
-		err := syscall.Kill(-proc.Pid, syscall.SIGKILL)
-		if err == nil {
-			return nil
-		}
-		if strings.Contains(err.Error(), "no such process") {
-			return nil
-		}
-		return err
-	}
-	if err := resolver(); err != nil {
+// killProcess sends SIGKILL to proc's whole process group and reaps proc
+// itself, so a killed Chrome process never lingers as a zombie until
+// gotenberg's own process exits.
+func killProcess(logger xlog.Logger, proc *os.Process) error {
+	const op string = "chrome.killProcess"
+	logger.DebugOpf(op, "killing Google Chrome headless process (pid %d)...", proc.Pid)
+	err := syscall.Kill(-proc.Pid, syscall.SIGKILL)
+	if err != nil && !strings.Contains(err.Error(), "no such process") {
 		return xerror.New(op, err)
 	}
-	return nil
-}
-
-func restart(logger xlog.Logger, proc *os.Process, ignoreCertificateErrors bool) error {
-	const op string = "chrome.restart"
-	logger.DebugOp(op, "restarting Google Chrome headless process using port 9222...")
-	resolver := func() error {
-		// kill the existing process first.
-		if err := kill(logger, proc); err != nil {
-			return err
-		}
-		cmd, err := cmd(logger, ignoreCertificateErrors)
-		if err != nil {
-			return err
-		}
-		// we try to restart the process.
-		xexec.LogBeforeExecute(logger, cmd)
-		if err := cmd.Start(); err != nil {
-			return err
-		}
-		// if the process failed to restart correctly,
-		// we have to restart it again.
-		isViable, _ := IsViable(logger)
-		if !isViable {
-			return restart(logger, cmd.Process, ignoreCertificateErrors)
-		}
-		return nil
-	}
-	if err := resolver(); err != nil {
-		return xerror.New(op, err)
+	if _, waitErr := proc.Wait(); waitErr != nil {
+		logger.DebugOpf(op, "failed to reap Google Chrome headless process (pid %d): %v", proc.Pid, waitErr)
 	}
 	return nil
 }
 
 // IsViable checks if Google Chrome is healthy.
 func IsViable(logger xlog.Logger) (bool, error) {
-	const (
-		op                string = "chrome.IsViable"
-		maxViabilityTests int    = 20
-	)
+	return isViableAt(logger, endpoint, restartBackoffMax, maxViabilityTests)
+}
+
+// isViableAt checks if the Google Chrome headless process exposing its
+// DevTools endpoint at addr is healthy, retrying with a capped exponential
+// backoff (from restartBackoffMin up to backoffMax) instead of busy-looping
+// at a fixed interval, and giving up after maxTests attempts. Callers pick
+// backoffMax/maxTests to fit how long they can afford to wait: see
+// IsViable (the long-lived process, boosted by a generous budget) versus
+// the ephemeral path's tighter one in launchEphemeral and
+// Instance.IsViable.
+func isViableAt(logger xlog.Logger, addr string, backoffMax time.Duration, maxTests int) (bool, error) {
+	const op string = "chrome.isViableAt"
 	viable := func() (bool, error) {
 		ctx, cancel := context.WithCancel(context.Background())
 		defer cancel()
-		endpoint := "http://localhost:9222"
 		logger.DebugOpf(
 			op,
 			"checking Google Chrome headless process viability via endpoint '%s/json/version'",
-			endpoint,
+			addr,
 		)
-		v, err := devtool.New(endpoint).Version(ctx)
+		v, err := devtool.New(addr).Version(ctx)
 		if err != nil {
 			logger.DebugOpf(
 				op,
@@ -163,24 +589,27 @@ func IsViable(logger xlog.Logger) (bool, error) {
 	}
 	result := false
 	var err error
+	backoff := restartBackoffMin
 
-	for i := 0; i < maxViabilityTests && !result; i++ {
-		warmup(logger)
+	for i := 0; i < maxTests && !result; i++ {
+		warmup(logger, backoff)
+		backoff *= 2
+		if backoff > backoffMax {
+			backoff = backoffMax
+		}
 		result, err = viable()
 	}
 	return result, err
 }
 
-func warmup(logger xlog.Logger) {
-	const (
-		op      string  = "chrome.warmup"
-		seconds float64 = 0.5
-	)
-	warmupTime := xtime.Duration(seconds)
+// warmup waits for d, giving Google Chrome time to come up before the next
+// viability check; see isViableAt.
+func warmup(logger xlog.Logger, d time.Duration) {
+	const op string = "chrome.warmup"
 	logger.DebugOpf(
 		op,
 		"waiting '%v' for allowing Google Chrome to warmup",
-		warmupTime,
+		d,
 	)
-	time.Sleep(warmupTime)
+	time.Sleep(d)
 }
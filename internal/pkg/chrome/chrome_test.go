@@ -0,0 +1,324 @@
+package chrome
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// silentLogger is a no-op xlog.Logger for exercising functions that only
+// log on the side, without pulling in a real logger implementation.
+type silentLogger struct{}
+
+func (silentLogger) DebugOp(op, message string)                   {}
+func (silentLogger) DebugOpf(op, format string, a ...interface{}) {}
+
+func TestMustDisableSandboxOverride(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{"true", "true", true},
+		{"case insensitive TRUE", "TRUE", true},
+		{"one", "1", true},
+		{"false", "false", false},
+		{"zero", "0", false},
+		{"unrecognized value", "maybe", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Setenv("CHROME_DISABLE_SANDBOX", tt.value)
+			defer os.Unsetenv("CHROME_DISABLE_SANDBOX")
+			if got := mustDisableSandbox(); got != tt.want {
+				t.Errorf("mustDisableSandbox() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustDisableSandboxFallsBackToAutoDetection(t *testing.T) {
+	os.Unsetenv("CHROME_DISABLE_SANDBOX")
+	want := runtime.GOOS == "linux" && os.Geteuid() == 0
+	if got := mustDisableSandbox(); got != want {
+		t.Errorf("mustDisableSandbox() = %v, want %v", got, want)
+	}
+}
+
+func TestSanitizeExtraArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{
+			name: "allowed flags pass through",
+			in:   []string{"--user-agent=gotenberg", "--disable-features=Foo"},
+			want: []string{"--user-agent=gotenberg", "--disable-features=Foo"},
+		},
+		{
+			name: "disallowed flags are dropped",
+			in:   []string{"--user-data-dir=/tmp/evil", "--remote-debugging-port=1234"},
+			want: []string{},
+		},
+		{
+			name: "mixed allowed and disallowed",
+			in:   []string{"--enable-features=Bar", "--no-sandbox"},
+			want: []string{"--enable-features=Bar"},
+		},
+		{
+			name: "empty input",
+			in:   []string{},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeExtraArgs(silentLogger{}, tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sanitizeExtraArgs(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// withFastRetries shrinks the backoff knobs launchWithRetries reads so
+// tests don't wait out the real production backoff, and caps the attempt
+// count at attempts. It returns a func to restore the originals.
+func withFastRetries(attempts int) func() {
+	origMin, origMax, origAttempts := restartBackoffMin, restartBackoffMax, maxRestartAttempts
+	restartBackoffMin = time.Millisecond
+	restartBackoffMax = time.Millisecond
+	maxRestartAttempts = attempts
+	return func() {
+		restartBackoffMin, restartBackoffMax, maxRestartAttempts = origMin, origMax, origAttempts
+	}
+}
+
+func TestLaunchWithRetriesGivesUpAfterMaxAttempts(t *testing.T) {
+	defer withFastRetries(3)()
+
+	var calls int32
+	launch := func() (*exec.Cmd, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("chrome refuses to start")
+	}
+
+	if _, err := launchWithRetries(silentLogger{}, launch); err == nil {
+		t.Fatal("launchWithRetries() error = nil, want non-nil once every attempt fails")
+	}
+	if got := atomic.LoadInt32(&calls); got != int32(maxRestartAttempts) {
+		t.Errorf("launch called %d times, want %d", got, maxRestartAttempts)
+	}
+}
+
+// TestSuperviseCountsOneRestartPerRelaunchRegardlessOfAttempts drives
+// supervise through two relaunches, each of which only succeeds on its
+// second internal attempt, then starves it until it gives up. restarts
+// must land on 2 (one per relaunch), not 4 (one per attempt) — the bug
+// this guards against conflated the two.
+func TestSuperviseCountsOneRestartPerRelaunchRegardlessOfAttempts(t *testing.T) {
+	defer withFastRetries(3)()
+	atomic.StoreUint64(&restarts, 0)
+	defer atomic.StoreUint64(&restarts, 0)
+	defer setHealth(currentState)
+
+	// calls 1 and 3 fail, 2 and 4 succeed (two relaunches, two attempts
+	// each); every call from the 5th on fails, starving supervise out.
+	succeedsOn := map[int32]bool{2: true, 4: true}
+	var calls int32
+	launch := func() (*exec.Cmd, error) {
+		n := atomic.AddInt32(&calls, 1)
+		if succeedsOn[n] {
+			c := exec.Command("true")
+			if err := c.Start(); err != nil {
+				return nil, err
+			}
+			return c, nil
+		}
+		return nil, errors.New("chrome still starting")
+	}
+
+	initial := exec.Command("true")
+	if err := initial.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	supervise(silentLogger{}, initial, launch)
+
+	if got := atomic.LoadUint64(&restarts); got != 2 {
+		t.Errorf("restarts = %d, want 2", got)
+	}
+	if healthy, _ := Health(); healthy {
+		t.Error("Health() healthy = true, want false once supervise gives up")
+	}
+}
+
+func TestHealthReportsCurrentStateAndRestartCount(t *testing.T) {
+	origState := currentState
+	defer setHealth(origState)
+	atomic.StoreUint64(&restarts, 0)
+	defer atomic.StoreUint64(&restarts, 0)
+
+	setHealth(healthHealthy)
+	atomic.AddUint64(&restarts, 2)
+	if healthy, count := Health(); !healthy || count != 2 {
+		t.Errorf("Health() = (%v, %d), want (true, 2)", healthy, count)
+	}
+
+	setHealth(healthUnhealthy)
+	if healthy, _ := Health(); healthy {
+		t.Errorf("Health() healthy = true, want false after setHealth(healthUnhealthy)")
+	}
+}
+
+// withRemoteState saves endpoint and remote, and restores them once the
+// test is done, so Connect can be exercised without leaking state into
+// other tests.
+func withRemoteState() func() {
+	origEndpoint, origRemote := endpoint, remote
+	return func() { endpoint, remote = origEndpoint, origRemote }
+}
+
+func TestConnectSucceedsAndMarksChromeHealthy(t *testing.T) {
+	defer withRemoteState()()
+	defer withFastRetries(1)()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Browser":"HeadlessChrome/test","Protocol-Version":"1.3"}`))
+	}))
+	defer srv.Close()
+
+	if err := Connect(silentLogger{}, srv.URL); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if endpoint != srv.URL {
+		t.Errorf("endpoint = %q, want %q", endpoint, srv.URL)
+	}
+	if !remote {
+		t.Error("remote = false, want true after Connect()")
+	}
+	if healthy, _ := Health(); !healthy {
+		t.Error("Health() healthy = false, want true after a successful Connect()")
+	}
+}
+
+func TestConnectFailsAndMarksChromeUnhealthyWhenEndpointUnreachable(t *testing.T) {
+	defer withRemoteState()()
+	defer withFastRetries(1)()
+	wasRemote := remote
+	previousEndpoint := endpoint
+
+	if err := Connect(silentLogger{}, "http://127.0.0.1:0"); err == nil {
+		t.Fatal("Connect() error = nil, want non-nil for an unreachable endpoint")
+	}
+	if healthy, _ := Health(); healthy {
+		t.Error("Health() healthy = true, want false after a failed Connect()")
+	}
+	if remote != wasRemote {
+		t.Errorf("remote = %v, want unchanged (%v) after a failed Connect()", remote, wasRemote)
+	}
+	if endpoint != previousEndpoint {
+		t.Errorf("endpoint = %q, want unchanged (%q) after a failed Connect()", endpoint, previousEndpoint)
+	}
+}
+
+func TestBootUsesConnectWhenChromeRemoteURLIsSet(t *testing.T) {
+	defer withRemoteState()()
+	defer withFastRetries(1)()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Browser":"HeadlessChrome/test","Protocol-Version":"1.3"}`))
+	}))
+	defer srv.Close()
+
+	os.Setenv("CHROME_REMOTE_URL", srv.URL)
+	defer os.Unsetenv("CHROME_REMOTE_URL")
+
+	if err := Boot(silentLogger{}, false, LauncherOptions{}); err != nil {
+		t.Fatalf("Boot() error = %v", err)
+	}
+	if !remote {
+		t.Error("remote = false, want true when CHROME_REMOTE_URL is set")
+	}
+	if endpoint != srv.URL {
+		t.Errorf("endpoint = %q, want %q", endpoint, srv.URL)
+	}
+}
+
+func TestEphemeralSemaphoreCapsAtMaxConcurrentInstances(t *testing.T) {
+	if got := cap(ephemeralSemaphore); got != maxConcurrentInstances {
+		t.Errorf("cap(ephemeralSemaphore) = %d, want %d", got, maxConcurrentInstances)
+	}
+}
+
+func TestInstanceCloseKillsProcessRemovesUserDataDirAndReleasesSemaphoreSlot(t *testing.T) {
+	userDataDir, err := ioutil.TempDir("", "gotenberg-chrome-test")
+	if err != nil {
+		t.Fatalf("TempDir() error = %v", err)
+	}
+
+	c := exec.Command("sleep", "30")
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// claim the slot StartEphemeral would have claimed for this instance.
+	before := len(ephemeralSemaphore)
+	ephemeralSemaphore <- struct{}{}
+
+	instance := &Instance{proc: c.Process, endpoint: "http://localhost:0", userDataDir: userDataDir}
+
+	if err := instance.Close(silentLogger{}); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, statErr := os.Stat(userDataDir); !os.IsNotExist(statErr) {
+		t.Errorf("user-data-dir %q was not removed by Close(), stat err = %v", userDataDir, statErr)
+	}
+
+	if got := len(ephemeralSemaphore); got != before {
+		t.Errorf("len(ephemeralSemaphore) = %d after Close(), want %d: Close() did not release its slot", got, before)
+	}
+}
+
+func TestInstanceCloseIsIdempotent(t *testing.T) {
+	userDataDir, err := ioutil.TempDir("", "gotenberg-chrome-test")
+	if err != nil {
+		t.Fatalf("TempDir() error = %v", err)
+	}
+
+	c := exec.Command("sleep", "30")
+	c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	before := len(ephemeralSemaphore)
+	ephemeralSemaphore <- struct{}{}
+
+	instance := &Instance{proc: c.Process, endpoint: "http://localhost:0", userDataDir: userDataDir}
+
+	if err := instance.Close(silentLogger{}); err != nil {
+		t.Fatalf("first Close() error = %v", err)
+	}
+	if err := instance.Close(silentLogger{}); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+
+	if got := len(ephemeralSemaphore); got != before {
+		t.Errorf("len(ephemeralSemaphore) = %d after two Close() calls, want %d: a second Close() must be a no-op rather than over-releasing it", got, before)
+	}
+}